@@ -0,0 +1,403 @@
+package rados
+
+/*
+#cgo LDFLAGS: -lrados
+#include "stdlib.h"
+#include "string.h"
+#include "rados/librados.h"
+*/
+import "C"
+
+import (
+    "errors"
+    "fmt"
+    "io"
+    "sync/atomic"
+    "time"
+    "unsafe"
+)
+
+// ErrTimeout is returned by Completion.WaitFor when the timeout
+// elapses before the underlying RADOS operation completes.
+var ErrTimeout = errors.New("rados: operation timed out")
+
+// ErrCancelled is returned by Completion.Wait and Completion.WaitFor
+// once Cancel has been called on the Completion.
+var ErrCancelled = errors.New("rados: operation cancelled")
+
+// Completion represents an in-flight asynchronous RADOS operation
+// started by one of the *Async methods on Context or Object.
+//
+// A background goroutine owns the underlying rados_completion_t until
+// the operation finishes, at which point it is released and any
+// associated buffers are freed; callers never touch the C completion
+// directly.
+type Completion struct {
+    comp C.rados_completion_t
+    ctx  *Context
+    done chan struct{}
+
+    onComplete func()
+    buffers    []unsafe.Pointer
+
+    err error
+    n   int
+
+    cancelled int32
+}
+
+// newCompletion allocates a librados completion. The caller must
+// follow up with submit once it has issued the actual rados_aio_*
+// call against c.comp; the completion is not tracked and its run
+// goroutine is not started until submit confirms the op was queued.
+func (ctx *Context) newCompletion() (*Completion, error) {
+    c := &Completion{ctx: ctx, done: make(chan struct{})}
+
+    if cerr := C.rados_aio_create_completion(nil, nil, nil, &c.comp); cerr < 0 {
+        return nil, fmt.Errorf("RADOS create completion: %s", strerror(cerr))
+    }
+
+    return c, nil
+}
+
+// keep records a cgo-allocated buffer that must stay alive for the
+// lifetime of the asynchronous operation, to be freed once it
+// finishes.
+func (c *Completion) keep(p unsafe.Pointer) {
+    c.buffers = append(c.buffers, p)
+}
+
+// submit finalizes a completion after the caller has made its
+// rados_aio_* call against c.comp, passing that call's return code.
+//
+// If the call failed, the op was never queued with RADOS, so there is
+// nothing for run's rados_aio_wait_for_complete to wait on: submit
+// tears c down synchronously instead (release the completion, free
+// its kept buffers) and returns the error without tracking c or
+// starting its goroutine. Leaving an untracked, un-started completion
+// around here would either leak its buffers or, worse, leak a
+// goroutine blocked forever in rados_aio_wait_for_complete on an op
+// that was never submitted.
+//
+// On success, c is registered with the owning Rados handle (so
+// Release can drain it) and its completion goroutine is started.
+func (c *Completion) submit(cerr C.int, errContext string) error {
+    if cerr < 0 {
+        C.rados_aio_release(c.comp)
+        for _, b := range c.buffers {
+            C.free(b)
+        }
+
+        return fmt.Errorf("%s: %s", errContext, strerror(cerr))
+    }
+
+    c.ctx.rados.trackCompletion(c)
+    go c.run()
+
+    return nil
+}
+
+// run waits for the operation to reach the "complete" stage, records
+// its result, and releases all resources associated with it. It is
+// the only code that touches c.comp after the op is submitted.
+func (c *Completion) run() {
+    C.rados_aio_wait_for_complete(c.comp)
+
+    if cerr := C.rados_aio_get_return_value(c.comp); cerr < 0 {
+        c.err = fmt.Errorf("RADOS async op: %s", strerror(cerr))
+    } else {
+        c.n = int(cerr)
+    }
+
+    if c.onComplete != nil {
+        c.onComplete()
+    }
+
+    C.rados_aio_release(c.comp)
+    for _, b := range c.buffers {
+        C.free(b)
+    }
+
+    c.ctx.rados.untrackCompletion(c)
+    close(c.done)
+}
+
+// Wait blocks until the operation finishes and returns its error, if
+// any.
+func (c *Completion) Wait() error {
+    if atomic.LoadInt32(&c.cancelled) != 0 {
+        select {
+        case <-c.done:
+        default:
+            return ErrCancelled
+        }
+    }
+
+    <-c.done
+
+    return c.err
+}
+
+// WaitFor blocks until the operation finishes or timeout elapses,
+// whichever comes first, returning ErrTimeout in the latter case. The
+// operation itself is not aborted by a timeout; it continues in the
+// background and is drained by Rados.Release.
+func (c *Completion) WaitFor(timeout time.Duration) error {
+    if atomic.LoadInt32(&c.cancelled) != 0 {
+        select {
+        case <-c.done:
+        default:
+            return ErrCancelled
+        }
+    }
+
+    select {
+    case <-c.done:
+        return c.err
+    case <-time.After(timeout):
+        return ErrTimeout
+    }
+}
+
+// waitDone blocks until the operation finishes, ignoring Cancel. It
+// is used by Rados.drainCompletions, which must not let a cancelled
+// but still in-flight op race rados_shutdown: Wait's cancellation
+// short-circuit is for callers giving up on a straggler, not for
+// teardown, which has to know the op's goroutine is really done with
+// c.comp before the cluster handle goes away.
+func (c *Completion) waitDone() {
+    <-c.done
+}
+
+// Done returns a channel that is closed once the operation finishes,
+// for use in select statements alongside other events.
+func (c *Completion) Done() <-chan struct{} {
+    return c.done
+}
+
+// IsComplete reports whether the operation has reached the "complete"
+// (acknowledged) stage.
+func (c *Completion) IsComplete() bool {
+    select {
+    case <-c.done:
+        return true
+    default:
+        return C.rados_aio_is_complete(c.comp) != 0
+    }
+}
+
+// IsSafe reports whether the operation has reached the "safe"
+// (durably committed) stage.
+func (c *Completion) IsSafe() bool {
+    select {
+    case <-c.done:
+        return true
+    default:
+        return C.rados_aio_is_safe(c.comp) != 0
+    }
+}
+
+// Cancel detaches the caller from the completion without waiting for
+// it to finish: subsequent Wait/WaitFor calls return ErrCancelled
+// immediately if the operation hasn't already completed. The
+// underlying RADOS operation is not aborted; it keeps running in the
+// background and is drained by Rados.Release like any other
+// outstanding completion.
+func (c *Completion) Cancel() {
+    atomic.StoreInt32(&c.cancelled, 1)
+}
+
+// Err returns the result of the operation, or nil if it has not yet
+// finished.
+func (c *Completion) Err() error {
+    select {
+    case <-c.done:
+        return c.err
+    default:
+        return nil
+    }
+}
+
+// cMallocBytes copies data into a newly C.malloc'd buffer, returning
+// nil for an empty slice. The caller is responsible for freeing it
+// (typically via Completion.keep, once the buffer is handed to an
+// async librados call).
+func cMallocBytes(data []byte) *C.char {
+    if len(data) == 0 {
+        return nil
+    }
+
+    cdata := (*C.char)(C.malloc(C.size_t(len(data))))
+    C.memcpy(unsafe.Pointer(cdata), unsafe.Pointer(&data[0]), C.size_t(len(data)))
+
+    return cdata
+}
+
+// PutAsync starts an asynchronous write of the complete contents of
+// the named object, overwriting (and truncating) anything already
+// there. The returned Completion must be waited on before its result
+// is known.
+func (ctx *Context) PutAsync(oid string, data []byte) (*Completion, error) {
+    c, err := ctx.newCompletion()
+    if err != nil {
+        return nil, err
+    }
+
+    coid := C.CString(oid)
+    c.keep(unsafe.Pointer(coid))
+
+    cdata := cMallocBytes(data)
+    if cdata != nil {
+        c.keep(unsafe.Pointer(cdata))
+    }
+
+    cerr := C.rados_aio_write_full(ctx.ioctx, coid, c.comp, cdata, C.size_t(len(data)))
+    if err := c.submit(cerr, fmt.Sprintf("RADOS put async %s", oid)); err != nil {
+        return nil, err
+    }
+
+    return c, nil
+}
+
+// GetAsync starts an asynchronous read of the complete contents of
+// the named object. It stats the object first (synchronously) to
+// size the read buffer, then starts the read; buf is only valid to
+// read once the returned Completion has finished.
+func (ctx *Context) GetAsync(oid string) (*Completion, []byte, error) {
+    info, err := ctx.Stat(oid)
+    if err != nil {
+        return nil, nil, err
+    }
+
+    buf := make([]byte, info.Size())
+
+    c, err := ctx.readAsync(oid, buf, 0)
+    if err != nil {
+        return nil, nil, err
+    }
+
+    return c, buf, nil
+}
+
+// StatAsync starts an asynchronous metadata lookup of the named
+// object. The returned ObjectInfo is only populated once the
+// Completion has finished.
+func (ctx *Context) StatAsync(oid string) (*Completion, *ObjectInfo, error) {
+    c, err := ctx.newCompletion()
+    if err != nil {
+        return nil, nil, err
+    }
+
+    coid := C.CString(oid)
+    c.keep(unsafe.Pointer(coid))
+
+    psize := (*C.uint64_t)(C.malloc(C.size_t(unsafe.Sizeof(C.uint64_t(0)))))
+    pmtime := (*C.time_t)(C.malloc(C.size_t(unsafe.Sizeof(C.time_t(0)))))
+    c.keep(unsafe.Pointer(psize))
+    c.keep(unsafe.Pointer(pmtime))
+
+    info := &ObjectInfo{name: oid}
+    c.onComplete = func() {
+        if c.err == nil {
+            info.size = uint64(*psize)
+            info.modTime = time.Unix(int64(*pmtime), 0)
+        }
+    }
+
+    cerr := C.rados_aio_stat(ctx.ioctx, coid, c.comp, psize, pmtime)
+    if err := c.submit(cerr, fmt.Sprintf("RADOS stat async %s", oid)); err != nil {
+        return nil, nil, err
+    }
+
+    return c, info, nil
+}
+
+// RemoveAsync starts an asynchronous removal of the named object.
+func (ctx *Context) RemoveAsync(oid string) (*Completion, error) {
+    c, err := ctx.newCompletion()
+    if err != nil {
+        return nil, err
+    }
+
+    coid := C.CString(oid)
+    c.keep(unsafe.Pointer(coid))
+
+    cerr := C.rados_aio_remove(ctx.ioctx, coid, c.comp)
+    if err := c.submit(cerr, fmt.Sprintf("RADOS remove async %s", oid)); err != nil {
+        return nil, err
+    }
+
+    return c, nil
+}
+
+// readAsync is the shared implementation behind GetAsync and
+// Object.ReadAtAsync: it starts an asynchronous read of len(p) bytes
+// at offset off, copying the result into p once the operation
+// finishes.
+func (ctx *Context) readAsync(oid string, p []byte, off int64) (*Completion, error) {
+    c, err := ctx.newCompletion()
+    if err != nil {
+        return nil, err
+    }
+
+    coid := C.CString(oid)
+    c.keep(unsafe.Pointer(coid))
+
+    var cbuf *C.char
+    if len(p) > 0 {
+        cbuf = (*C.char)(C.malloc(C.size_t(len(p))))
+        c.keep(unsafe.Pointer(cbuf))
+    }
+
+    c.onComplete = func() {
+        if c.err != nil {
+            return
+        }
+
+        if cbuf != nil && c.n > 0 {
+            copy(p, C.GoBytes(unsafe.Pointer(cbuf), C.int(c.n)))
+        }
+
+        if c.n < len(p) {
+            c.err = io.EOF
+        }
+    }
+
+    cerr := C.rados_aio_read(ctx.ioctx, coid, c.comp, cbuf, C.size_t(len(p)), C.uint64_t(off))
+    if err := c.submit(cerr, fmt.Sprintf("RADOS read async %s", oid)); err != nil {
+        return nil, err
+    }
+
+    return c, nil
+}
+
+// ReadAtAsync starts an asynchronous read of len(p) bytes from the
+// object starting at byte offset off. p is only valid to read once
+// the returned Completion has finished.
+func (o *Object) ReadAtAsync(p []byte, off int64) (*Completion, error) {
+    return o.ctx.readAsync(o.name, p, off)
+}
+
+// WriteAtAsync starts an asynchronous write of len(p) bytes to the
+// object starting at byte offset off.
+func (o *Object) WriteAtAsync(p []byte, off int64) (*Completion, error) {
+    c, err := o.ctx.newCompletion()
+    if err != nil {
+        return nil, err
+    }
+
+    coid := C.CString(o.name)
+    c.keep(unsafe.Pointer(coid))
+
+    cbuf := cMallocBytes(p)
+    if cbuf != nil {
+        c.keep(unsafe.Pointer(cbuf))
+    }
+
+    cerr := C.rados_aio_write(o.ctx.ioctx, coid, c.comp, cbuf, C.size_t(len(p)), C.uint64_t(off))
+    if err := c.submit(cerr, fmt.Sprintf("RADOS write async %s", o.name)); err != nil {
+        return nil, err
+    }
+
+    return c, nil
+}
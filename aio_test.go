@@ -0,0 +1,196 @@
+package rados
+
+import (
+    "bytes"
+    "io"
+    "testing"
+    "time"
+)
+
+func Test_RadosAsyncPutGet(t *testing.T) {
+    rados, err := New("")
+    fatalOnError(t, err, "New")
+    defer rados.Release()
+
+    ctx, err := rados.NewContext("test")
+    fatalOnError(t, err, "NewContext")
+    defer ctx.Release()
+
+    name := "test-async-object"
+    data := []byte("async test data")
+
+    putC, err := ctx.PutAsync(name, data)
+    fatalOnError(t, err, "PutAsync")
+    fatalOnError(t, putC.Wait(), "PutAsync Wait")
+    defer ctx.Remove(name)
+
+    getC, buf, err := ctx.GetAsync(name)
+    fatalOnError(t, err, "GetAsync")
+    fatalOnError(t, getC.Wait(), "GetAsync Wait")
+
+    if !bytes.Equal(data, buf) {
+        t.Errorf("async data mismatch, was %s, expected %s", buf, data)
+    }
+}
+
+func Test_RadosAsyncStat(t *testing.T) {
+    rados, err := New("")
+    fatalOnError(t, err, "New")
+    defer rados.Release()
+
+    ctx, err := rados.NewContext("test")
+    fatalOnError(t, err, "NewContext")
+    defer ctx.Release()
+
+    name := "test-async-stat-object"
+    data := []byte("stat me")
+
+    fatalOnError(t, ctx.Put(name, data), "Put")
+    defer ctx.Remove(name)
+
+    statC, info, err := ctx.StatAsync(name)
+    fatalOnError(t, err, "StatAsync")
+    fatalOnError(t, statC.Wait(), "StatAsync Wait")
+
+    if info.Size() != int64(len(data)) {
+        t.Errorf("async stat size mismatch, was %d, expected %d", info.Size(), len(data))
+    }
+}
+
+func Test_RadosAsyncRemove(t *testing.T) {
+    rados, err := New("")
+    fatalOnError(t, err, "New")
+    defer rados.Release()
+
+    ctx, err := rados.NewContext("test")
+    fatalOnError(t, err, "NewContext")
+    defer ctx.Release()
+
+    name := "test-async-remove-object"
+    fatalOnError(t, ctx.Put(name, []byte("x")), "Put")
+
+    removeC, err := ctx.RemoveAsync(name)
+    fatalOnError(t, err, "RemoveAsync")
+    fatalOnError(t, removeC.Wait(), "RemoveAsync Wait")
+
+    if _, err := ctx.Stat(name); err == nil {
+        t.Errorf("object %s should have been removed", name)
+    }
+}
+
+// Test_RadosAsyncFailedOpDoesNotWedgeRelease covers the scenario the
+// completion-leak bug would have broken: an async op that ends in
+// error (here, removing an object that was never created) must still
+// let its Completion finish and must not leave Release hanging. This
+// doesn't exercise a submission failure specifically (those require a
+// RADOS call that fails before the op is even queued, which isn't
+// reachable through the public API against a healthy cluster), but it
+// does exercise the wait/drain path every *Async op and Release share.
+func Test_RadosAsyncFailedOpDoesNotWedgeRelease(t *testing.T) {
+    rados, err := New("")
+    fatalOnError(t, err, "New")
+
+    ctx, err := rados.NewContext("test")
+    fatalOnError(t, err, "NewContext")
+    defer ctx.Release()
+
+    removeC, err := ctx.RemoveAsync("test-object-that-does-not-exist")
+    fatalOnError(t, err, "RemoveAsync")
+
+    if err := removeC.Wait(); err == nil {
+        t.Errorf("RemoveAsync of a nonexistent object should have failed")
+    }
+
+    done := make(chan struct{})
+    go func() {
+        rados.Release()
+        close(done)
+    }()
+
+    select {
+    case <-done:
+    case <-time.After(10 * time.Second):
+        t.Fatal("Release did not return after a failed async op; a completion may be wedged")
+    }
+}
+
+func Test_RadosCompletionDoneAndIsComplete(t *testing.T) {
+    rados, err := New("")
+    fatalOnError(t, err, "New")
+    defer rados.Release()
+
+    ctx, err := rados.NewContext("test")
+    fatalOnError(t, err, "NewContext")
+    defer ctx.Release()
+
+    name := "test-async-done-object"
+    putC, err := ctx.PutAsync(name, []byte("done channel test"))
+    fatalOnError(t, err, "PutAsync")
+
+    select {
+    case <-putC.Done():
+    case <-time.After(10 * time.Second):
+        t.Fatal("Done() channel never closed")
+    }
+
+    if !putC.IsComplete() {
+        t.Errorf("IsComplete should be true once Done has fired")
+    }
+    if err := putC.Err(); err != nil {
+        t.Errorf("Put should have succeeded, got %v", err)
+    }
+
+    ctx.Remove(name)
+}
+
+func Test_RadosCompletionWaitForTimeout(t *testing.T) {
+    rados, err := New("")
+    fatalOnError(t, err, "New")
+    defer rados.Release()
+
+    ctx, err := rados.NewContext("test")
+    fatalOnError(t, err, "NewContext")
+    defer ctx.Release()
+
+    name := "test-async-timeout-object"
+    putC, err := ctx.PutAsync(name, []byte("timeout test"))
+    fatalOnError(t, err, "PutAsync")
+
+    if err := putC.WaitFor(0); err != ErrTimeout && err != nil {
+        t.Errorf("WaitFor(0) should time out (or occasionally win the race and succeed), got %v", err)
+    }
+
+    fatalOnError(t, putC.Wait(), "Wait")
+    ctx.Remove(name)
+}
+
+func Test_RadosObjectReadAtWriteAt(t *testing.T) {
+    rados, err := New("")
+    fatalOnError(t, err, "New")
+    defer rados.Release()
+
+    ctx, err := rados.NewContext("test")
+    fatalOnError(t, err, "NewContext")
+    defer ctx.Release()
+
+    name := "test-readat-writeat-object"
+    obj, err := ctx.Create(name)
+    fatalOnError(t, err, "Create")
+    defer ctx.Remove(name)
+
+    data := []byte("0123456789")
+    n, err := obj.WriteAt(data, 5)
+    fatalOnError(t, err, "WriteAt")
+    if n != len(data) {
+        t.Errorf("WriteAt wrote %d bytes, expected %d", n, len(data))
+    }
+
+    buf := make([]byte, len(data))
+    n, err = obj.ReadAt(buf, 5)
+    if err != nil && err != io.EOF {
+        fatalOnError(t, err, "ReadAt")
+    }
+    if n != len(data) || !bytes.Equal(buf[:n], data) {
+        t.Errorf("ReadAt mismatch, was %q, expected %q", buf[:n], data)
+    }
+}
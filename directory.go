@@ -0,0 +1,133 @@
+package rados
+
+import (
+    "fmt"
+    "sort"
+    "strings"
+)
+
+// blobPrefix marks an omap value as a reference to a blob rather than
+// a subdirectory marker, following the virtual-directory convention
+// used by Ceph-backed object storage drivers: a directory is an omap
+// object whose keys are entry names and whose values are either
+// "blob:<uuid>" (a file) or empty (a subdirectory).
+const blobPrefix = "blob:"
+
+// DirEntry describes one entry of a Directory: either a file
+// referencing a blob, or a subdirectory.
+type DirEntry struct {
+    Name  string
+    IsDir bool
+    Blob  string // blob reference for files; empty for directories
+}
+
+// Directory layers a virtual filesystem namespace over the omap of a
+// single RADOS object, so that List/Stat/Rename/Delete don't need to
+// be reimplemented by every caller of the lower-level Omap methods.
+type Directory struct {
+    ctx *Context
+    oid string
+}
+
+// Directory returns a Directory backed by the named object's omap.
+// The object is not required to exist in advance: the first write
+// (PutFile/Mkdir) will create it.
+func (ctx *Context) Directory(oid string) *Directory {
+    return &Directory{ctx: ctx, oid: oid}
+}
+
+func dirEntryValue(blob string) []byte {
+    if blob == "" {
+        return nil
+    }
+
+    return []byte(blobPrefix + blob)
+}
+
+func parseDirEntry(name string, value []byte) DirEntry {
+    if s := string(value); strings.HasPrefix(s, blobPrefix) {
+        return DirEntry{Name: name, Blob: strings.TrimPrefix(s, blobPrefix)}
+    }
+
+    return DirEntry{Name: name, IsDir: true}
+}
+
+// List returns every entry in the directory, in name order.
+func (d *Directory) List() ([]DirEntry, error) {
+    const pageSize = 1000
+
+    entries := make([]DirEntry, 0)
+    startAfter := ""
+
+    for {
+        vals, more, err := d.ctx.GetOmapRange(d.oid, startAfter, "", pageSize)
+        if err != nil {
+            return nil, err
+        }
+
+        names := make([]string, 0, len(vals))
+        for name := range vals {
+            names = append(names, name)
+        }
+        sort.Strings(names)
+
+        for _, name := range names {
+            entries = append(entries, parseDirEntry(name, vals[name]))
+            startAfter = name
+        }
+
+        if !more || len(vals) == 0 {
+            break
+        }
+    }
+
+    return entries, nil
+}
+
+// Stat returns the entry for the named file or subdirectory.
+func (d *Directory) Stat(name string) (DirEntry, error) {
+    vals, err := d.ctx.GetOmap(d.oid, []string{name})
+    if err != nil {
+        return DirEntry{}, err
+    }
+
+    value, ok := vals[name]
+    if !ok {
+        return DirEntry{}, fmt.Errorf("RADOS directory %s: %s: not found", d.oid, name)
+    }
+
+    return parseDirEntry(name, value), nil
+}
+
+// PutFile adds or overwrites a file entry referencing the given blob.
+func (d *Directory) PutFile(name, blob string) error {
+    return d.ctx.SetOmap(d.oid, map[string][]byte{name: dirEntryValue(blob)})
+}
+
+// Mkdir adds a subdirectory entry.
+func (d *Directory) Mkdir(name string) error {
+    return d.ctx.SetOmap(d.oid, map[string][]byte{name: nil})
+}
+
+// Rename moves an entry (file or subdirectory) from oldName to
+// newName by rewriting its omap entry, without touching the
+// underlying blob.
+func (d *Directory) Rename(oldName, newName string) error {
+    entry, err := d.Stat(oldName)
+    if err != nil {
+        return err
+    }
+
+    if err := d.ctx.SetOmap(d.oid, map[string][]byte{newName: dirEntryValue(entry.Blob)}); err != nil {
+        return err
+    }
+
+    return d.ctx.RemoveOmapKeys(d.oid, []string{oldName})
+}
+
+// Delete removes an entry (file or subdirectory) from the directory.
+// It does not recurse into subdirectories or remove the underlying
+// blob of a file entry.
+func (d *Directory) Delete(name string) error {
+    return d.ctx.RemoveOmapKeys(d.oid, []string{name})
+}
@@ -0,0 +1,147 @@
+package rados
+
+/*
+#cgo LDFLAGS: -lrados
+#include "stdlib.h"
+#include "rados/librados.h"
+*/
+import "C"
+
+import (
+    "fmt"
+    "unsafe"
+)
+
+// Context represents an IO context: a handle for reading, writing, and
+// managing objects within a single pool.
+type Context struct {
+    ioctx C.rados_ioctx_t
+    rados *Rados
+    pool  string
+
+    stripeSize int64
+    namespace  string
+}
+
+// NewContext creates an IO context for the named pool. The returned
+// Context should be released with Release when it is no longer
+// needed.
+func (r *Rados) NewContext(pool string) (*Context, error) {
+    cpool := C.CString(pool)
+    defer C.free(unsafe.Pointer(cpool))
+
+    ctx := &Context{rados: r, pool: pool, stripeSize: DefaultStripeSize}
+
+    if cerr := C.rados_ioctx_create(r.rados, cpool, &ctx.ioctx); cerr < 0 {
+        return nil, fmt.Errorf("RADOS create context %s: %s", pool, strerror(cerr))
+    }
+
+    return ctx, nil
+}
+
+// Release destroys the IO context, freeing any resources associated
+// with it.
+func (ctx *Context) Release() error {
+    C.rados_ioctx_destroy(ctx.ioctx)
+
+    return nil
+}
+
+// Create creates a new, empty object with the given name.
+func (ctx *Context) Create(oid string) (*Object, error) {
+    if err := ctx.Put(oid, []byte{}); err != nil {
+        return nil, err
+    }
+
+    return &Object{ctx: ctx, name: oid}, nil
+}
+
+// Open returns a handle to an existing object, failing if it is not
+// present in the pool.
+func (ctx *Context) Open(oid string) (*Object, error) {
+    if _, err := ctx.Stat(oid); err != nil {
+        return nil, err
+    }
+
+    return &Object{ctx: ctx, name: oid}, nil
+}
+
+// Put writes data as the complete contents of the named object,
+// overwriting (and truncating) anything already there.
+func (ctx *Context) Put(oid string, data []byte) error {
+    c, err := ctx.PutAsync(oid, data)
+    if err != nil {
+        return err
+    }
+
+    return ctx.waitWrite(c)
+}
+
+// Get returns the complete contents of the named object.
+func (ctx *Context) Get(oid string) ([]byte, error) {
+    c, buf, err := ctx.GetAsync(oid)
+    if err != nil {
+        return nil, err
+    }
+
+    if err := ctx.waitRead(c); err != nil {
+        return nil, err
+    }
+
+    return buf, nil
+}
+
+// Stat retrieves metadata about the named object.
+func (ctx *Context) Stat(oid string) (*ObjectInfo, error) {
+    c, info, err := ctx.StatAsync(oid)
+    if err != nil {
+        return nil, err
+    }
+
+    if err := ctx.waitMeta(c); err != nil {
+        return nil, err
+    }
+
+    return info, nil
+}
+
+// Remove deletes the named object from the pool.
+func (ctx *Context) Remove(oid string) error {
+    c, err := ctx.RemoveAsync(oid)
+    if err != nil {
+        return err
+    }
+
+    return ctx.waitMeta(c)
+}
+
+// waitRead waits for a Completion started by a read operation,
+// applying the Rados handle's ReadTimeout if one is configured.
+func (ctx *Context) waitRead(c *Completion) error {
+    if ctx.rados.ReadTimeout > 0 {
+        return c.WaitFor(ctx.rados.ReadTimeout)
+    }
+
+    return c.Wait()
+}
+
+// waitWrite waits for a Completion started by a write operation,
+// applying the Rados handle's WriteTimeout if one is configured.
+func (ctx *Context) waitWrite(c *Completion) error {
+    if ctx.rados.WriteTimeout > 0 {
+        return c.WaitFor(ctx.rados.WriteTimeout)
+    }
+
+    return c.Wait()
+}
+
+// waitMeta waits for a Completion started by a metadata operation
+// (Stat, Remove), applying the Rados handle's MetadataTimeout if one
+// is configured.
+func (ctx *Context) waitMeta(c *Completion) error {
+    if ctx.rados.MetadataTimeout > 0 {
+        return c.WaitFor(ctx.rados.MetadataTimeout)
+    }
+
+    return c.Wait()
+}
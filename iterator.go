@@ -0,0 +1,171 @@
+package rados
+
+/*
+#cgo LDFLAGS: -lrados
+#include "stdlib.h"
+#include "errno.h"
+#include "rados/librados.h"
+*/
+import "C"
+
+import (
+    "fmt"
+    "io"
+    "sync"
+    "unsafe"
+)
+
+// SetNamespace scopes this Context's object listing and I/O to the
+// given namespace (e.g. "keep"). An empty string selects the default
+// namespace.
+func (ctx *Context) SetNamespace(ns string) {
+    cns := C.CString(ns)
+    defer C.free(unsafe.Pointer(cns))
+
+    C.rados_ioctx_set_namespace(ctx.ioctx, cns)
+    ctx.namespace = ns
+}
+
+// ObjectIterator walks every object in a pool (or, after SetNamespace,
+// every object in one namespace of it), in no particular order.
+type ObjectIterator struct {
+    listCtx C.rados_list_ctx_t
+}
+
+// ListObjects returns an iterator over every object visible through
+// ctx. The returned ObjectIterator must be closed with Close when the
+// caller is done with it.
+func (ctx *Context) ListObjects() (*ObjectIterator, error) {
+    it := &ObjectIterator{}
+
+    if cerr := C.rados_nobjects_list_open(ctx.ioctx, &it.listCtx); cerr < 0 {
+        return nil, fmt.Errorf("RADOS list objects: %s", strerror(cerr))
+    }
+
+    return it, nil
+}
+
+// Next returns the name, namespace, and locator of the next object,
+// returning io.EOF once every object has been visited.
+func (it *ObjectIterator) Next() (name, nspace, locator string, err error) {
+    var centry, ckey, cnspace *C.char
+
+    cerr := C.rados_nobjects_list_next(it.listCtx, &centry, &ckey, &cnspace)
+    if cerr == -C.ENOENT {
+        return "", "", "", io.EOF
+    }
+    if cerr < 0 {
+        return "", "", "", fmt.Errorf("RADOS list objects next: %s", strerror(cerr))
+    }
+
+    name = C.GoString(centry)
+    if ckey != nil {
+        locator = C.GoString(ckey)
+    }
+    if cnspace != nil {
+        nspace = C.GoString(cnspace)
+    }
+
+    return name, nspace, locator, nil
+}
+
+// Seek repositions the iterator to the given placement-group hash
+// position (0 through 0xffffffff), returning the position actually
+// sought to. It is mainly useful for splitting a listing across
+// multiple cursors, as WalkObjects does.
+func (it *ObjectIterator) Seek(pos uint32) uint32 {
+    return uint32(C.rados_nobjects_list_seek(it.listCtx, C.uint32_t(pos)))
+}
+
+// HashPosition returns the placement-group hash position of the
+// object most recently returned by Next.
+func (it *ObjectIterator) HashPosition() uint32 {
+    return uint32(C.rados_nobjects_list_get_pg_hash_position(it.listCtx))
+}
+
+// Close releases the iterator's resources.
+func (it *ObjectIterator) Close() error {
+    C.rados_nobjects_list_close(it.listCtx)
+
+    return nil
+}
+
+// objectHashSpace is the size of the placement-group hash space that
+// object names are scattered across (hash positions are uint32).
+const objectHashSpace = uint64(1) << 32
+
+// WalkObjects calls fn once for every object visible through ctx,
+// fanning the listing out across workers goroutines. Each worker
+// holds its own ObjectIterator, seeked to a disjoint slice of the
+// placement-group hash space, so workers make progress independently
+// instead of contending on a single cursor. This mirrors the
+// RadosIndexWorkers pattern Arvados keepstore uses to parallelize full
+// pool scans.
+//
+// WalkObjects returns the first error encountered, after which other
+// workers' errors are discarded; fn may still be called concurrently
+// from multiple goroutines after that point, so it must be safe for
+// concurrent use.
+func (ctx *Context) WalkObjects(workers int, fn func(oid string) error) error {
+    if workers < 1 {
+        workers = 1
+    }
+
+    span := objectHashSpace / uint64(workers)
+    errs := make(chan error, workers)
+
+    var wg sync.WaitGroup
+    for w := 0; w < workers; w++ {
+        start := uint32(uint64(w) * span)
+        end := uint32(uint64(w+1) * span)
+        last := w == workers-1
+
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+            errs <- ctx.walkObjectsRange(start, end, last, fn)
+        }()
+    }
+
+    wg.Wait()
+    close(errs)
+
+    for err := range errs {
+        if err != nil {
+            return err
+        }
+    }
+
+    return nil
+}
+
+// walkObjectsRange drives a single WalkObjects worker over the
+// placement-group hash range [start, end), or [start, end] for the
+// last worker, which must run to the end of the hash space.
+func (ctx *Context) walkObjectsRange(start, end uint32, last bool, fn func(oid string) error) error {
+    it, err := ctx.ListObjects()
+    if err != nil {
+        return err
+    }
+    defer it.Close()
+
+    it.Seek(start)
+
+    for {
+        name, _, _, err := it.Next()
+        if err == io.EOF {
+            return nil
+        }
+        if err != nil {
+            return err
+        }
+
+        if !last && it.HashPosition() >= end {
+            return nil
+        }
+
+        if err := fn(name); err != nil {
+            return err
+        }
+    }
+}
@@ -0,0 +1,80 @@
+package rados
+
+import (
+    "fmt"
+    "io"
+    "sync"
+    "testing"
+)
+
+func Test_RadosListObjects(t *testing.T) {
+    rados, err := New("")
+    fatalOnError(t, err, "New")
+    defer rados.Release()
+
+    ctx, err := rados.NewContext("test")
+    fatalOnError(t, err, "NewContext")
+    defer ctx.Release()
+
+    names := []string{"test-iter-object-1", "test-iter-object-2", "test-iter-object-3"}
+    for _, name := range names {
+        fatalOnError(t, ctx.Put(name, []byte("x")), "Put")
+        defer ctx.Remove(name)
+    }
+
+    it, err := ctx.ListObjects()
+    fatalOnError(t, err, "ListObjects")
+    defer it.Close()
+
+    seen := make(map[string]bool)
+    for {
+        name, _, _, err := it.Next()
+        if err == io.EOF {
+            break
+        }
+        fatalOnError(t, err, "Next")
+        seen[name] = true
+    }
+
+    for _, name := range names {
+        if !seen[name] {
+            t.Errorf("ListObjects did not visit %s", name)
+        }
+    }
+}
+
+func Test_RadosWalkObjects(t *testing.T) {
+    rados, err := New("")
+    fatalOnError(t, err, "New")
+    defer rados.Release()
+
+    ctx, err := rados.NewContext("test")
+    fatalOnError(t, err, "NewContext")
+    defer ctx.Release()
+
+    const n = 20
+    names := make([]string, n)
+    for i := 0; i < n; i++ {
+        names[i] = fmt.Sprintf("test-walk-object-%d", i)
+        fatalOnError(t, ctx.Put(names[i], []byte("x")), "Put")
+        defer ctx.Remove(names[i])
+    }
+
+    var mu sync.Mutex
+    seen := make(map[string]int)
+
+    err = ctx.WalkObjects(4, func(oid string) error {
+        mu.Lock()
+        seen[oid]++
+        mu.Unlock()
+
+        return nil
+    })
+    fatalOnError(t, err, "WalkObjects")
+
+    for _, name := range names {
+        if seen[name] != 1 {
+            t.Errorf("WalkObjects visited %s %d times, expected exactly once", name, seen[name])
+        }
+    }
+}
@@ -0,0 +1,99 @@
+package rados
+
+import (
+    "testing"
+    "time"
+)
+
+func Test_RadosLockExclusive(t *testing.T) {
+    rados, err := New("")
+    fatalOnError(t, err, "New")
+    defer rados.Release()
+
+    ctx, err := rados.NewContext("test")
+    fatalOnError(t, err, "NewContext")
+    defer ctx.Release()
+
+    oid := "test-lock-object"
+    fatalOnError(t, ctx.Put(oid, []byte("lock me")), "Put")
+    defer ctx.Remove(oid)
+
+    lock, err := ctx.Lock(oid, "mylock", "cookie-1", LockOptions{})
+    fatalOnError(t, err, "Lock")
+
+    if _, err := ctx.Lock(oid, "mylock", "cookie-2", LockOptions{}); err != ErrLockBusy {
+        t.Errorf("second exclusive Lock should have returned ErrLockBusy, got %v", err)
+    }
+
+    exclusive, _, lockers, err := lock.Listers()
+    fatalOnError(t, err, "Listers")
+    if !exclusive {
+        t.Errorf("lock should be reported as exclusive")
+    }
+    if len(lockers) != 1 || lockers[0].Cookie != "cookie-1" {
+        t.Errorf("Listers returned %+v, expected one locker with cookie-1", lockers)
+    }
+
+    fatalOnError(t, lock.Unlock(), "Unlock")
+
+    if err := lock.Unlock(); err != ErrLockNotFound {
+        t.Errorf("second Unlock should have returned ErrLockNotFound, got %v", err)
+    }
+}
+
+func Test_RadosLockRenew(t *testing.T) {
+    rados, err := New("")
+    fatalOnError(t, err, "New")
+    defer rados.Release()
+
+    ctx, err := rados.NewContext("test")
+    fatalOnError(t, err, "NewContext")
+    defer ctx.Release()
+
+    oid := "test-lock-renew-object"
+    fatalOnError(t, ctx.Put(oid, []byte("renew me")), "Put")
+    defer ctx.Remove(oid)
+
+    lock, err := ctx.Lock(oid, "renewlock", "cookie-1", LockOptions{Duration: time.Minute})
+    fatalOnError(t, err, "Lock")
+
+    fatalOnError(t, lock.Renew(), "Renew")
+
+    _, _, lockers, err := lock.Listers()
+    fatalOnError(t, err, "Listers")
+    if len(lockers) != 1 || lockers[0].Cookie != "cookie-1" {
+        t.Errorf("lock should still be held under cookie-1 after Renew, got %+v", lockers)
+    }
+
+    fatalOnError(t, lock.Unlock(), "Unlock")
+}
+
+func Test_RadosLockShared(t *testing.T) {
+    rados, err := New("")
+    fatalOnError(t, err, "New")
+    defer rados.Release()
+
+    ctx, err := rados.NewContext("test")
+    fatalOnError(t, err, "NewContext")
+    defer ctx.Release()
+
+    oid := "test-lock-shared-object"
+    fatalOnError(t, ctx.Put(oid, []byte("share me")), "Put")
+    defer ctx.Remove(oid)
+
+    opts := LockOptions{Mode: LockShared, Tag: "readers"}
+
+    lockA, err := ctx.Lock(oid, "sharedlock", "cookie-a", opts)
+    fatalOnError(t, err, "Lock cookie-a")
+    defer lockA.Unlock()
+
+    lockB, err := ctx.Lock(oid, "sharedlock", "cookie-b", opts)
+    fatalOnError(t, err, "Lock cookie-b")
+    defer lockB.Unlock()
+
+    _, _, lockers, err := lockA.Listers()
+    fatalOnError(t, err, "Listers")
+    if len(lockers) != 2 {
+        t.Errorf("shared lock should report 2 lockers, got %+v", lockers)
+    }
+}
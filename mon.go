@@ -0,0 +1,141 @@
+package rados
+
+/*
+#cgo LDFLAGS: -lrados
+#include "stdlib.h"
+#include "errno.h"
+#include "rados/librados.h"
+*/
+import "C"
+
+import (
+    "bytes"
+    "fmt"
+    "unsafe"
+)
+
+// PingMonitor sends a no-op command to the named monitor (e.g. "a",
+// "b", ...) and returns its reply, mainly useful as a liveness check.
+func (r *Rados) PingMonitor(id string) (string, error) {
+    cid := C.CString(id)
+    defer C.free(unsafe.Pointer(cid))
+
+    var coutstr *C.char
+    var coutstrlen C.size_t
+
+    if cerr := C.rados_ping_monitor(r.rados, cid, &coutstr, &coutstrlen); cerr < 0 {
+        return "", fmt.Errorf("RADOS ping monitor %s: %s", id, strerror(cerr))
+    }
+    defer C.rados_buffer_free(coutstr)
+
+    return C.GoStringN(coutstr, C.int(coutstrlen)), nil
+}
+
+// GetFSID returns the cluster's unique fsid.
+func (r *Rados) GetFSID() (string, error) {
+    bufSize := 64
+
+    for {
+        buf := make([]byte, bufSize)
+        cbuf, cbuflen := byteSliceToBuffer(buf)
+
+        cerr := C.rados_cluster_fsid(r.rados, cbuf, cbuflen)
+        if cerr < 0 {
+            if cerr == -C.ERANGE {
+                bufSize *= 2
+                continue
+            }
+
+            return "", fmt.Errorf("RADOS get fsid: %s", strerror(cerr))
+        }
+
+        return string(buf[:int(cerr)]), nil
+    }
+}
+
+// SetConfigOption sets a Ceph configuration option on this cluster
+// handle (equivalent to a line in ceph.conf or a `--key value` CLI
+// flag).
+func (r *Rados) SetConfigOption(key, value string) error {
+    ckey := C.CString(key)
+    defer C.free(unsafe.Pointer(ckey))
+    cvalue := C.CString(value)
+    defer C.free(unsafe.Pointer(cvalue))
+
+    if cerr := C.rados_conf_set(r.rados, ckey, cvalue); cerr < 0 {
+        return fmt.Errorf("RADOS set config %s: %s", key, strerror(cerr))
+    }
+
+    return nil
+}
+
+// GetConfigOption returns the current value of a Ceph configuration
+// option on this cluster handle.
+func (r *Rados) GetConfigOption(key string) (string, error) {
+    ckey := C.CString(key)
+    defer C.free(unsafe.Pointer(ckey))
+
+    bufSize := 256
+    for {
+        buf := make([]byte, bufSize)
+        cbuf, cbuflen := byteSliceToBuffer(buf)
+
+        cerr := C.rados_conf_get(r.rados, ckey, cbuf, cbuflen)
+        if cerr < 0 {
+            if cerr == -C.ENAMETOOLONG || cerr == -C.ERANGE {
+                bufSize *= 2
+                continue
+            }
+
+            return "", fmt.Errorf("RADOS get config %s: %s", key, strerror(cerr))
+        }
+
+        if n := bytes.IndexByte(buf, 0); n >= 0 {
+            buf = buf[:n]
+        }
+
+        return string(buf), nil
+    }
+}
+
+// MonCommand issues a JSON-formatted admin command (the same commands
+// accepted by the `ceph` CLI, e.g. `{"prefix": "osd tree"}`) to the
+// cluster's monitors, returning its (possibly JSON) reply and any
+// human-readable status text.
+func (r *Rados) MonCommand(cmd []byte, inbuf []byte) (outbuf, outs []byte, err error) {
+    ccmd := C.CString(string(cmd))
+    defer C.free(unsafe.Pointer(ccmd))
+    ccmds := []*C.char{ccmd}
+
+    cinbuf, cinbuflen := byteSliceToBuffer(inbuf)
+
+    var coutbuf, couts *C.char
+    var coutbuflen, coutslen C.size_t
+
+    cerr := C.rados_mon_command(r.rados, &ccmds[0], C.size_t(len(ccmds)),
+        cinbuf, cinbuflen, &coutbuf, &coutbuflen, &couts, &coutslen)
+    if cerr < 0 {
+        return nil, nil, fmt.Errorf("RADOS mon command: %s", strerror(cerr))
+    }
+
+    if coutbuf != nil {
+        outbuf = C.GoBytes(unsafe.Pointer(coutbuf), C.int(coutbuflen))
+        C.rados_buffer_free(coutbuf)
+    }
+    if couts != nil {
+        outs = C.GoBytes(unsafe.Pointer(couts), C.int(coutslen))
+        C.rados_buffer_free(couts)
+    }
+
+    return outbuf, outs, nil
+}
+
+// WaitForLatestOSDMap blocks until this client has received the
+// latest OSD map from the monitors.
+func (r *Rados) WaitForLatestOSDMap() error {
+    if cerr := C.rados_wait_for_latest_osdmap(r.rados); cerr < 0 {
+        return fmt.Errorf("RADOS wait for latest osdmap: %s", strerror(cerr))
+    }
+
+    return nil
+}
@@ -0,0 +1,54 @@
+package rados
+
+import (
+    "testing"
+)
+
+func Test_RadosGetFSID(t *testing.T) {
+    rados, err := New("")
+    fatalOnError(t, err, "New")
+    defer rados.Release()
+
+    fsid, err := rados.GetFSID()
+    fatalOnError(t, err, "GetFSID")
+    if fsid == "" {
+        t.Errorf("GetFSID returned an empty fsid")
+    }
+}
+
+func Test_RadosConfigOption(t *testing.T) {
+    rados, err := New("")
+    fatalOnError(t, err, "New")
+    defer rados.Release()
+
+    // log_to_stderr is a boolean config option present on every Ceph
+    // client, so it's a safe one to round-trip without depending on
+    // cluster-specific configuration.
+    fatalOnError(t, rados.SetConfigOption("log_to_stderr", "true"), "SetConfigOption")
+
+    val, err := rados.GetConfigOption("log_to_stderr")
+    fatalOnError(t, err, "GetConfigOption")
+    if val != "true" {
+        t.Errorf("GetConfigOption returned %q, expected %q", val, "true")
+    }
+}
+
+func Test_RadosMonCommand(t *testing.T) {
+    rados, err := New("")
+    fatalOnError(t, err, "New")
+    defer rados.Release()
+
+    outbuf, _, err := rados.MonCommand([]byte(`{"prefix": "df", "format": "json"}`), nil)
+    fatalOnError(t, err, "MonCommand")
+    if len(outbuf) == 0 {
+        t.Errorf("MonCommand df returned an empty reply")
+    }
+}
+
+func Test_RadosWaitForLatestOSDMap(t *testing.T) {
+    rados, err := New("")
+    fatalOnError(t, err, "New")
+    defer rados.Release()
+
+    errorOnError(t, rados.WaitForLatestOSDMap(), "WaitForLatestOSDMap")
+}
@@ -0,0 +1,89 @@
+package rados
+
+import (
+    "io"
+    "os"
+    "time"
+)
+
+// ObjectInfo describes the metadata of a RADOS object as reported by
+// Context.Stat. It implements the os.FileInfo interface so
+// that it can be used interchangeably in code written against the
+// standard library's file abstractions.
+type ObjectInfo struct {
+    name    string
+    size    uint64
+    modTime time.Time
+}
+
+// Name returns the name of the object.
+func (oi *ObjectInfo) Name() string { return oi.name }
+
+// Size returns the size of the object in bytes.
+func (oi *ObjectInfo) Size() int64 { return int64(oi.size) }
+
+// Mode returns the object's permission bits. RADOS objects have no
+// notion of file permissions, so this is always a fixed value.
+func (oi *ObjectInfo) Mode() os.FileMode { return 0644 }
+
+// ModTime returns the last modification time of the object, as
+// reported by rados_stat.
+func (oi *ObjectInfo) ModTime() time.Time { return oi.modTime }
+
+// IsDir always returns false: RADOS pools are flat object namespaces.
+func (oi *ObjectInfo) IsDir() bool { return false }
+
+// Sys returns nil. It exists to satisfy the os.FileInfo interface.
+func (oi *ObjectInfo) Sys() interface{} { return nil }
+
+// Object is a handle to a single object within a Context. In addition
+// to the whole-object Put/Get methods on Context, it implements
+// io.ReaderAt and io.WriterAt for random-access I/O.
+type Object struct {
+    ctx  *Context
+    name string
+}
+
+// Name returns the name of the object.
+func (o *Object) Name() string { return o.name }
+
+// Size returns the object's current size, querying RADOS for up to
+// date metadata.
+func (o *Object) Size() int64 {
+    info, err := o.ctx.Stat(o.name)
+    if err != nil {
+        return 0
+    }
+
+    return info.Size()
+}
+
+// ReadAt reads len(p) bytes from the object starting at byte offset
+// off, implementing io.ReaderAt.
+func (o *Object) ReadAt(p []byte, off int64) (int, error) {
+    c, err := o.ReadAtAsync(p, off)
+    if err != nil {
+        return 0, err
+    }
+
+    if err := o.ctx.waitRead(c); err != nil && err != io.EOF {
+        return 0, err
+    }
+
+    return c.n, c.err
+}
+
+// WriteAt writes len(p) bytes to the object starting at byte offset
+// off, implementing io.WriterAt.
+func (o *Object) WriteAt(p []byte, off int64) (int, error) {
+    c, err := o.WriteAtAsync(p, off)
+    if err != nil {
+        return 0, err
+    }
+
+    if err := o.ctx.waitWrite(c); err != nil {
+        return 0, err
+    }
+
+    return len(p), nil
+}
@@ -0,0 +1,190 @@
+package rados
+
+/*
+#cgo LDFLAGS: -lrados
+#include "stdlib.h"
+#include "rados/librados.h"
+*/
+import "C"
+
+import (
+    "fmt"
+    "unsafe"
+)
+
+// SetOmap sets the given keys to the given values in the named
+// object's omap (key/value map). An empty value is a valid entry: the
+// Directory helper in directory.go uses this to mark a key as a subdirectory
+// rather than a file reference.
+func (ctx *Context) SetOmap(oid string, kv map[string][]byte) error {
+    if len(kv) == 0 {
+        return nil
+    }
+
+    coid := C.CString(oid)
+    defer C.free(unsafe.Pointer(coid))
+
+    n := len(kv)
+    ckeys := make([]*C.char, n)
+    cvals := make([]*C.char, n)
+    clens := make([]C.size_t, n)
+
+    i := 0
+    for k, v := range kv {
+        ckeys[i] = C.CString(k)
+        defer C.free(unsafe.Pointer(ckeys[i]))
+
+        if len(v) > 0 {
+            cvals[i] = (*C.char)(C.CBytes(v))
+            defer C.free(unsafe.Pointer(cvals[i]))
+        }
+
+        clens[i] = C.size_t(len(v))
+        i++
+    }
+
+    op := C.rados_create_write_op()
+    defer C.rados_release_write_op(op)
+
+    C.rados_write_op_omap_set(op, &ckeys[0], &cvals[0], &clens[0], C.size_t(n))
+
+    if cerr := C.rados_write_op_operate(op, ctx.ioctx, coid, nil, 0); cerr < 0 {
+        return fmt.Errorf("RADOS set omap %s: %s", oid, strerror(cerr))
+    }
+
+    return nil
+}
+
+// GetOmap returns the values of the given keys in the named object's
+// omap. Keys that are not present in the omap are simply absent from
+// the returned map.
+func (ctx *Context) GetOmap(oid string, keys []string) (map[string][]byte, error) {
+    coid := C.CString(oid)
+    defer C.free(unsafe.Pointer(coid))
+
+    ckeys := make([]*C.char, len(keys))
+    for i, k := range keys {
+        ckeys[i] = C.CString(k)
+        defer C.free(unsafe.Pointer(ckeys[i]))
+    }
+
+    var ckeysPtr **C.char
+    if len(ckeys) > 0 {
+        ckeysPtr = &ckeys[0]
+    }
+
+    op := C.rados_create_read_op()
+    defer C.rados_release_read_op(op)
+
+    var iter C.rados_omap_iter_t
+    var prval C.int
+
+    C.rados_read_op_omap_get_vals_by_keys(op, ckeysPtr, C.size_t(len(keys)), &iter, &prval)
+
+    if cerr := C.rados_read_op_operate(op, ctx.ioctx, coid, 0); cerr < 0 {
+        return nil, fmt.Errorf("RADOS get omap %s: %s", oid, strerror(cerr))
+    }
+    if prval < 0 {
+        return nil, fmt.Errorf("RADOS get omap %s: %s", oid, strerror(prval))
+    }
+
+    return drainOmapIter(iter)
+}
+
+// GetOmapRange returns up to maxReturn key/value pairs from the named
+// object's omap, in key order, whose keys sort after startAfter and
+// begin with filterPrefix (both may be empty). more reports whether
+// additional pairs remain beyond maxReturn; callers wanting the full
+// omap should keep calling with startAfter set to the last key seen
+// until more is false.
+func (ctx *Context) GetOmapRange(oid, startAfter, filterPrefix string, maxReturn uint64) (map[string][]byte, bool, error) {
+    coid := C.CString(oid)
+    defer C.free(unsafe.Pointer(coid))
+
+    cstartAfter := C.CString(startAfter)
+    defer C.free(unsafe.Pointer(cstartAfter))
+
+    cfilterPrefix := C.CString(filterPrefix)
+    defer C.free(unsafe.Pointer(cfilterPrefix))
+
+    op := C.rados_create_read_op()
+    defer C.rados_release_read_op(op)
+
+    var iter C.rados_omap_iter_t
+    var pmore C.uchar
+    var prval C.int
+
+    C.rados_read_op_omap_get_vals2(op, cstartAfter, cfilterPrefix, C.uint64_t(maxReturn), &iter, &pmore, &prval)
+
+    if cerr := C.rados_read_op_operate(op, ctx.ioctx, coid, 0); cerr < 0 {
+        return nil, false, fmt.Errorf("RADOS get omap range %s: %s", oid, strerror(cerr))
+    }
+    if prval < 0 {
+        return nil, false, fmt.Errorf("RADOS get omap range %s: %s", oid, strerror(prval))
+    }
+
+    vals, err := drainOmapIter(iter)
+    if err != nil {
+        return nil, false, err
+    }
+
+    return vals, pmore != 0, nil
+}
+
+// RemoveOmapKeys removes the given keys from the named object's omap.
+// If keys is empty, the entire omap is cleared.
+func (ctx *Context) RemoveOmapKeys(oid string, keys []string) error {
+    coid := C.CString(oid)
+    defer C.free(unsafe.Pointer(coid))
+
+    op := C.rados_create_write_op()
+    defer C.rados_release_write_op(op)
+
+    if len(keys) == 0 {
+        C.rados_write_op_omap_clear(op)
+    } else {
+        ckeys := make([]*C.char, len(keys))
+        for i, k := range keys {
+            ckeys[i] = C.CString(k)
+            defer C.free(unsafe.Pointer(ckeys[i]))
+        }
+
+        C.rados_write_op_omap_rm_keys(op, &ckeys[0], C.size_t(len(keys)))
+    }
+
+    if cerr := C.rados_write_op_operate(op, ctx.ioctx, coid, nil, 0); cerr < 0 {
+        return fmt.Errorf("RADOS remove omap keys %s: %s", oid, strerror(cerr))
+    }
+
+    return nil
+}
+
+// drainOmapIter reads every key/value pair out of an omap iterator
+// produced by one of the read_op_omap_get_vals* calls and closes it.
+func drainOmapIter(iter C.rados_omap_iter_t) (map[string][]byte, error) {
+    defer C.rados_omap_get_end(iter)
+
+    result := make(map[string][]byte)
+
+    for {
+        var ckey, cval *C.char
+        var clen C.size_t
+
+        if cerr := C.rados_omap_get_next(iter, &ckey, &cval, &clen); cerr < 0 {
+            return nil, fmt.Errorf("RADOS omap iterate: %s", strerror(cerr))
+        }
+
+        if ckey == nil {
+            break
+        }
+
+        var val []byte
+        if clen > 0 {
+            val = C.GoBytes(unsafe.Pointer(cval), C.int(clen))
+        }
+
+        result[C.GoString(ckey)] = val
+    }
+
+    return result, nil
+}
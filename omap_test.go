@@ -0,0 +1,118 @@
+package rados
+
+import (
+    "bytes"
+    "testing"
+)
+
+func Test_RadosOmap(t *testing.T) {
+    rados, err := New("")
+    fatalOnError(t, err, "New")
+    defer rados.Release()
+
+    ctx, err := rados.NewContext("test")
+    fatalOnError(t, err, "NewContext")
+    defer ctx.Release()
+
+    oid := "test-omap-object"
+    fatalOnError(t, ctx.Put(oid, []byte("omap carrier")), "Put")
+    defer ctx.Remove(oid)
+
+    fatalOnError(t, ctx.SetOmap(oid, map[string][]byte{
+        "a": []byte("1"),
+        "b": []byte("2"),
+        "c": []byte("3"),
+    }), "SetOmap")
+
+    vals, err := ctx.GetOmap(oid, []string{"a", "c", "missing"})
+    fatalOnError(t, err, "GetOmap")
+
+    if !bytes.Equal(vals["a"], []byte("1")) || !bytes.Equal(vals["c"], []byte("3")) {
+        t.Errorf("GetOmap returned %v, expected a=1 c=3", vals)
+    }
+    if _, ok := vals["missing"]; ok {
+        t.Errorf("GetOmap should not return an entry for a missing key")
+    }
+
+    all, more, err := ctx.GetOmapRange(oid, "", "", 10)
+    fatalOnError(t, err, "GetOmapRange")
+    if more {
+        t.Errorf("GetOmapRange should report no more entries past a page covering everything")
+    }
+    if len(all) != 3 {
+        t.Errorf("GetOmapRange returned %d entries, expected 3", len(all))
+    }
+
+    fatalOnError(t, ctx.RemoveOmapKeys(oid, []string{"a"}), "RemoveOmapKeys")
+
+    vals, err = ctx.GetOmap(oid, []string{"a", "b"})
+    fatalOnError(t, err, "GetOmap")
+    if _, ok := vals["a"]; ok {
+        t.Errorf("key a should have been removed")
+    }
+    if !bytes.Equal(vals["b"], []byte("2")) {
+        t.Errorf("key b should still be present")
+    }
+
+    fatalOnError(t, ctx.RemoveOmapKeys(oid, nil), "RemoveOmapKeys clear")
+
+    vals, err = ctx.GetOmap(oid, []string{"b", "c"})
+    fatalOnError(t, err, "GetOmap")
+    if len(vals) != 0 {
+        t.Errorf("RemoveOmapKeys with no keys should have cleared the whole omap, got %v", vals)
+    }
+}
+
+func Test_RadosDirectory(t *testing.T) {
+    rados, err := New("")
+    fatalOnError(t, err, "New")
+    defer rados.Release()
+
+    ctx, err := rados.NewContext("test")
+    fatalOnError(t, err, "NewContext")
+    defer ctx.Release()
+
+    oid := "test-directory-object"
+    defer ctx.Remove(oid)
+
+    dir := ctx.Directory(oid)
+
+    fatalOnError(t, dir.PutFile("report.txt", "blob-1"), "PutFile")
+    fatalOnError(t, dir.Mkdir("subdir"), "Mkdir")
+
+    entries, err := dir.List()
+    fatalOnError(t, err, "List")
+    if len(entries) != 2 {
+        t.Fatalf("List returned %d entries, expected 2", len(entries))
+    }
+
+    entry, err := dir.Stat("report.txt")
+    fatalOnError(t, err, "Stat report.txt")
+    if entry.IsDir || entry.Blob != "blob-1" {
+        t.Errorf("report.txt entry mismatch: %+v", entry)
+    }
+
+    entry, err = dir.Stat("subdir")
+    fatalOnError(t, err, "Stat subdir")
+    if !entry.IsDir {
+        t.Errorf("subdir entry should be a directory: %+v", entry)
+    }
+
+    fatalOnError(t, dir.Rename("report.txt", "report-final.txt"), "Rename")
+
+    if _, err := dir.Stat("report.txt"); err == nil {
+        t.Errorf("report.txt should no longer exist after Rename")
+    }
+
+    entry, err = dir.Stat("report-final.txt")
+    fatalOnError(t, err, "Stat report-final.txt")
+    if entry.Blob != "blob-1" {
+        t.Errorf("renamed entry should keep its blob reference, got %+v", entry)
+    }
+
+    fatalOnError(t, dir.Delete("subdir"), "Delete")
+
+    if _, err := dir.Stat("subdir"); err == nil {
+        t.Errorf("subdir should no longer exist after Delete")
+    }
+}
@@ -14,6 +14,8 @@ import "C"
 import (
     "bytes"
     "fmt"
+    "sync"
+    "time"
     "unsafe"
 )
 
@@ -24,6 +26,19 @@ type Rados struct {
     used     uint64
     avail    uint64
     nObjects uint64
+
+    // ReadTimeout, WriteTimeout, and MetadataTimeout, when non-zero,
+    // bound how long Context read (Get/ReadAt), write (Put/WriteAt),
+    // and metadata (Stat/Remove) operations will wait for completion
+    // before giving up with ErrTimeout. They are applied per
+    // operation, so a caller issuing many small requests can cancel
+    // stragglers without tearing down the whole connection.
+    ReadTimeout     time.Duration
+    WriteTimeout    time.Duration
+    MetadataTimeout time.Duration
+
+    completionsMu sync.Mutex
+    completions   map[*Completion]struct{}
 }
 
 // New returns a RADOS cluster handle that is used to create IO
@@ -112,15 +127,58 @@ func (r *Rados) NObjects() uint64 {
 
 // Release handle and disconnect from RADOS cluster.
 //
-// TODO: track all open ioctx, ensure all async operations have
-// completed before calling rados_shutdown, because it doesn't do that
-// itself.
+// Any asynchronous operations started with the *Async methods on
+// Context/Object that are still outstanding are drained first, since
+// rados_shutdown does not wait for them itself.
 func (r *Rados) Release() error {
+    r.drainCompletions()
+
     C.rados_shutdown(r.rados)
 
     return nil
 }
 
+// trackCompletion registers an in-flight Completion so that Release
+// can wait for it before shutting down the cluster handle.
+func (r *Rados) trackCompletion(c *Completion) {
+    r.completionsMu.Lock()
+    defer r.completionsMu.Unlock()
+
+    if r.completions == nil {
+        r.completions = make(map[*Completion]struct{})
+    }
+    r.completions[c] = struct{}{}
+}
+
+// untrackCompletion removes a Completion from the in-flight registry
+// once it has finished.
+func (r *Rados) untrackCompletion(c *Completion) {
+    r.completionsMu.Lock()
+    defer r.completionsMu.Unlock()
+
+    delete(r.completions, c)
+}
+
+// drainCompletions waits for every outstanding Completion to finish.
+// It waits unconditionally (via waitDone, not Wait) so that a
+// Completion someone has called Cancel on but that hasn't actually
+// finished yet still blocks shutdown: otherwise rados_shutdown could
+// run while that Completion's goroutine is still inside
+// rados_aio_wait_for_complete, racing cluster teardown against a live
+// cgo call on freed state.
+func (r *Rados) drainCompletions() {
+    r.completionsMu.Lock()
+    pending := make([]*Completion, 0, len(r.completions))
+    for c := range r.completions {
+        pending = append(pending, c)
+    }
+    r.completionsMu.Unlock()
+
+    for _, c := range pending {
+        c.waitDone()
+    }
+}
+
 // CreatePool creates the named pool in the given RADOS cluster.
 // CreatePool uses the default admin user and crush rule.
 //
@@ -0,0 +1,244 @@
+package rados
+
+import (
+    "fmt"
+    "io"
+    "strconv"
+)
+
+// DefaultStripeSize is the chunk size a StripedObject uses when its
+// Context hasn't called SetStripeSize.
+const DefaultStripeSize int64 = 4 << 20 // 4 MiB
+
+// stripedSizeXattr holds a StripedObject's total length, stored as a
+// decimal string xattr on its first chunk.
+const stripedSizeXattr = "striped-total-size"
+
+// SetStripeSize sets the chunk size used by StripedObjects created
+// from this Context afterwards. It has no effect on StripedObjects
+// already created.
+func (ctx *Context) SetStripeSize(size int64) {
+    ctx.stripeSize = size
+}
+
+// StripedObject transparently splits a large blob across multiple
+// fixed-size RADOS objects (named "<name>.<chunkIndex>"), so that no
+// single RADOS object grows past the chunk size. This mirrors the
+// approach Ceph-backed block/object storage drivers take on top of
+// librados to keep individual objects small enough to replicate
+// efficiently across OSDs.
+type StripedObject struct {
+    ctx        *Context
+    name       string
+    stripeSize int64
+}
+
+// CreateStriped returns a StripedObject named name, using the
+// Context's current stripe size. The object is created lazily: an
+// empty StripedObject takes no space until written to.
+func (ctx *Context) CreateStriped(name string) (*StripedObject, error) {
+    return &StripedObject{ctx: ctx, name: name, stripeSize: ctx.stripeSize}, nil
+}
+
+// chunkName returns the name of the chunkIndex'th underlying object.
+func (so *StripedObject) chunkName(chunkIndex int64) string {
+    return fmt.Sprintf("%s.%d", so.name, chunkIndex)
+}
+
+// Size returns the StripedObject's total length, as recorded in the
+// size xattr on its first chunk. A StripedObject that has never been
+// written to has size 0.
+func (so *StripedObject) Size() (int64, error) {
+    buf, err := so.ctx.getXattrInternal(so.chunkName(0), stripedSizeXattr)
+    if err == ErrNotFound {
+        // Chunk 0 (or its size xattr) doesn't exist yet: the
+        // StripedObject has never been written to, so it reads as
+        // empty. Any other error (timeout, permission, network) is
+        // propagated instead of being mistaken for "never written" --
+        // Put and WriteAt call Size to decide what to truncate or how
+        // far the recorded length extends, so masking a real error
+        // here would silently corrupt an existing object.
+        return 0, nil
+    }
+    if err != nil {
+        return 0, err
+    }
+
+    return strconv.ParseInt(string(buf), 10, 64)
+}
+
+// Stat returns metadata about the StripedObject, as if it were a
+// single object.
+func (so *StripedObject) Stat() (*ObjectInfo, error) {
+    size, err := so.Size()
+    if err != nil {
+        return nil, err
+    }
+
+    return &ObjectInfo{name: so.name, size: uint64(size)}, nil
+}
+
+func (so *StripedObject) setSize(size int64) error {
+    return so.ctx.setXattrInternal(so.chunkName(0), stripedSizeXattr, []byte(strconv.FormatInt(size, 10)))
+}
+
+// WriteAt writes len(p) bytes to the StripedObject starting at byte
+// offset off, transparently splitting the write across as many chunk
+// objects as necessary. It implements io.WriterAt.
+func (so *StripedObject) WriteAt(p []byte, off int64) (int, error) {
+    written := 0
+
+    for written < len(p) {
+        pos := off + int64(written)
+        chunkIndex := pos / so.stripeSize
+        chunkOff := pos % so.stripeSize
+
+        n := len(p) - written
+        if max := int(so.stripeSize - chunkOff); n > max {
+            n = max
+        }
+
+        chunk := &Object{ctx: so.ctx, name: so.chunkName(chunkIndex)}
+        if _, err := chunk.WriteAt(p[written:written+n], chunkOff); err != nil {
+            return written, err
+        }
+
+        written += n
+    }
+
+    if end := off + int64(written); end > 0 {
+        size, err := so.Size()
+        if err != nil {
+            return written, err
+        }
+
+        if end > size {
+            if err := so.setSize(end); err != nil {
+                return written, err
+            }
+        }
+    }
+
+    return written, nil
+}
+
+// ReadAt reads len(p) bytes from the StripedObject starting at byte
+// offset off, transparently gathering them from as many chunk objects
+// as necessary. It implements io.ReaderAt.
+func (so *StripedObject) ReadAt(p []byte, off int64) (int, error) {
+    size, err := so.Size()
+    if err != nil {
+        return 0, err
+    }
+
+    if off >= size {
+        return 0, io.EOF
+    }
+
+    want := len(p)
+    if int64(want) > size-off {
+        want = int(size - off)
+    }
+
+    read := 0
+    for read < want {
+        pos := off + int64(read)
+        chunkIndex := pos / so.stripeSize
+        chunkOff := pos % so.stripeSize
+
+        n := want - read
+        if max := int(so.stripeSize - chunkOff); n > max {
+            n = max
+        }
+
+        chunk := &Object{ctx: so.ctx, name: so.chunkName(chunkIndex)}
+        if _, err := chunk.ReadAt(p[read:read+n], chunkOff); err != nil && err != io.EOF {
+            return read, err
+        }
+
+        read += n
+    }
+
+    if read < len(p) {
+        return read, io.EOF
+    }
+
+    return read, nil
+}
+
+// Put writes data as the complete contents of the StripedObject,
+// overwriting anything already there and removing any now-unused
+// trailing chunks.
+func (so *StripedObject) Put(data []byte) error {
+    oldSize, err := so.Size()
+    if err != nil {
+        return err
+    }
+
+    if _, err := so.WriteAt(data, 0); err != nil {
+        return err
+    }
+
+    if err := so.setSize(int64(len(data))); err != nil {
+        return err
+    }
+
+    oldChunks := (oldSize + so.stripeSize - 1) / so.stripeSize
+    newChunks := (int64(len(data)) + so.stripeSize - 1) / so.stripeSize
+
+    for i := newChunks; i < oldChunks; i++ {
+        if err := so.ctx.Remove(so.chunkName(i)); err != nil {
+            return err
+        }
+    }
+
+    return nil
+}
+
+// Get returns the complete contents of the StripedObject.
+func (so *StripedObject) Get() ([]byte, error) {
+    size, err := so.Size()
+    if err != nil {
+        return nil, err
+    }
+
+    buf := make([]byte, size)
+    if _, err := so.ReadAt(buf, 0); err != nil && err != io.EOF {
+        return nil, err
+    }
+
+    return buf, nil
+}
+
+// Remove deletes every chunk backing the StripedObject. A
+// StripedObject that was created but never written to has no chunks
+// on RADOS at all (Size's lazy "never written" case), so Remove is a
+// no-op for it rather than an error: removing chunk 0 of an object
+// that was never created would otherwise fail with ENOENT.
+func (so *StripedObject) Remove() error {
+    buf, err := so.ctx.getXattrInternal(so.chunkName(0), stripedSizeXattr)
+    if err == ErrNotFound {
+        return nil
+    }
+    if err != nil {
+        return err
+    }
+
+    size, err := strconv.ParseInt(string(buf), 10, 64)
+    if err != nil {
+        return err
+    }
+
+    chunks := (size + so.stripeSize - 1) / so.stripeSize
+    if chunks == 0 {
+        chunks = 1
+    }
+
+    for i := int64(0); i < chunks; i++ {
+        if err := so.ctx.Remove(so.chunkName(i)); err != nil {
+            return err
+        }
+    }
+
+    return nil
+}
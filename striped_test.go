@@ -0,0 +1,76 @@
+package rados
+
+import (
+    "bytes"
+    "testing"
+)
+
+func Test_RadosStripedObjectPutGet(t *testing.T) {
+    rados, err := New("")
+    fatalOnError(t, err, "New")
+    defer rados.Release()
+
+    ctx, err := rados.NewContext("test")
+    fatalOnError(t, err, "NewContext")
+    defer ctx.Release()
+
+    ctx.SetStripeSize(8)
+
+    name := "test-striped-object"
+    so, err := ctx.CreateStriped(name)
+    fatalOnError(t, err, "CreateStriped")
+    defer so.Remove()
+
+    data := []byte("this blob spans several 8-byte chunks")
+    fatalOnError(t, so.Put(data), "Put")
+
+    size, err := so.Size()
+    fatalOnError(t, err, "Size")
+    if size != int64(len(data)) {
+        t.Errorf("Size was %d, expected %d", size, len(data))
+    }
+
+    got, err := so.Get()
+    fatalOnError(t, err, "Get")
+    if !bytes.Equal(got, data) {
+        t.Errorf("Get returned %q, expected %q", got, data)
+    }
+
+    // Put shorter data and make sure the reported size and contents
+    // shrink to match it.
+    shorter := []byte("short")
+    fatalOnError(t, so.Put(shorter), "Put shorter")
+
+    size, err = so.Size()
+    fatalOnError(t, err, "Size after shrink")
+    if size != int64(len(shorter)) {
+        t.Errorf("Size after shrink was %d, expected %d", size, len(shorter))
+    }
+
+    got, err = so.Get()
+    fatalOnError(t, err, "Get after shrink")
+    if !bytes.Equal(got, shorter) {
+        t.Errorf("Get after shrink returned %q, expected %q", got, shorter)
+    }
+}
+
+func Test_RadosStripedObjectRemoveNeverWritten(t *testing.T) {
+    rados, err := New("")
+    fatalOnError(t, err, "New")
+    defer rados.Release()
+
+    ctx, err := rados.NewContext("test")
+    fatalOnError(t, err, "NewContext")
+    defer ctx.Release()
+
+    so, err := ctx.CreateStriped("test-striped-object-never-written")
+    fatalOnError(t, err, "CreateStriped")
+
+    size, err := so.Size()
+    fatalOnError(t, err, "Size")
+    if size != 0 {
+        t.Errorf("Size of a never-written StripedObject was %d, expected 0", size)
+    }
+
+    errorOnError(t, so.Remove(), "Remove on a never-written StripedObject should be a no-op")
+}
@@ -0,0 +1,151 @@
+package rados
+
+/*
+#cgo LDFLAGS: -lrados
+#include "stdlib.h"
+#include "errno.h"
+#include "rados/librados.h"
+*/
+import "C"
+
+import (
+    "errors"
+    "fmt"
+    "unsafe"
+)
+
+// ErrNotFound is returned by the internal xattr helpers when the
+// object or attribute being looked up doesn't exist.
+var ErrNotFound = errors.New("rados: not found")
+
+// setXattrInternal sets a single xattr on an object. It backs both
+// the public SetXattr/GetXattr API below and StripedObject's
+// total-length bookkeeping in striped.go.
+func (ctx *Context) setXattrInternal(oid, name string, value []byte) error {
+    coid := C.CString(oid)
+    defer C.free(unsafe.Pointer(coid))
+    cname := C.CString(name)
+    defer C.free(unsafe.Pointer(cname))
+
+    cval, cvallen := byteSliceToBuffer(value)
+
+    if cerr := C.rados_setxattr(ctx.ioctx, coid, cname, cval, cvallen); cerr < 0 {
+        return fmt.Errorf("RADOS setxattr %s/%s: %s", oid, name, strerror(cerr))
+    }
+
+    return nil
+}
+
+// getXattrInternal reads a single xattr from an object, growing its
+// read buffer and retrying as needed.
+func (ctx *Context) getXattrInternal(oid, name string) ([]byte, error) {
+    coid := C.CString(oid)
+    defer C.free(unsafe.Pointer(coid))
+    cname := C.CString(name)
+    defer C.free(unsafe.Pointer(cname))
+
+    bufSize := 256
+    for {
+        buf := make([]byte, bufSize)
+        cbuf, cbuflen := byteSliceToBuffer(buf)
+
+        cerr := C.rados_getxattr(ctx.ioctx, coid, cname, cbuf, cbuflen)
+        if cerr < 0 {
+            if cerr == -C.ERANGE {
+                bufSize *= 2
+                continue
+            }
+            if cerr == -C.ENOENT || cerr == -C.ENODATA {
+                return nil, ErrNotFound
+            }
+
+            return nil, fmt.Errorf("RADOS getxattr %s/%s: %s", oid, name, strerror(cerr))
+        }
+
+        return buf[:int(cerr)], nil
+    }
+}
+
+// SetXattr sets an extended attribute on the named object. This is
+// how per-object metadata (mtime markers, trash/deleted-at tombstones,
+// and the like) can be stored without a side-channel database.
+func (ctx *Context) SetXattr(oid, name string, value []byte) error {
+    return ctx.setXattrInternal(oid, name, value)
+}
+
+// GetXattr returns the value of an extended attribute on the named
+// object, or ErrNotFound if the object or the attribute doesn't
+// exist.
+func (ctx *Context) GetXattr(oid, name string) ([]byte, error) {
+    return ctx.getXattrInternal(oid, name)
+}
+
+// RemoveXattr removes an extended attribute from the named object.
+func (ctx *Context) RemoveXattr(oid, name string) error {
+    coid := C.CString(oid)
+    defer C.free(unsafe.Pointer(coid))
+    cname := C.CString(name)
+    defer C.free(unsafe.Pointer(cname))
+
+    if cerr := C.rados_rmxattr(ctx.ioctx, coid, cname); cerr < 0 {
+        return fmt.Errorf("RADOS rmxattr %s/%s: %s", oid, name, strerror(cerr))
+    }
+
+    return nil
+}
+
+// ListXattrs returns every extended attribute set on the named
+// object.
+func (ctx *Context) ListXattrs(oid string) (map[string][]byte, error) {
+    coid := C.CString(oid)
+    defer C.free(unsafe.Pointer(coid))
+
+    var iter C.rados_xattrs_iter_t
+    if cerr := C.rados_getxattrs(ctx.ioctx, coid, &iter); cerr < 0 {
+        return nil, fmt.Errorf("RADOS getxattrs %s: %s", oid, strerror(cerr))
+    }
+    defer C.rados_getxattrs_end(iter)
+
+    result := make(map[string][]byte)
+    for {
+        var cname, cval *C.char
+        var clen C.size_t
+
+        if cerr := C.rados_getxattrs_next(iter, &cname, &cval, &clen); cerr < 0 {
+            return nil, fmt.Errorf("RADOS getxattrs %s: %s", oid, strerror(cerr))
+        }
+
+        if cname == nil {
+            break
+        }
+
+        var val []byte
+        if clen > 0 {
+            val = C.GoBytes(unsafe.Pointer(cval), C.int(clen))
+        }
+
+        result[C.GoString(cname)] = val
+    }
+
+    return result, nil
+}
+
+// SetXattr sets an extended attribute on the object.
+func (o *Object) SetXattr(name string, value []byte) error {
+    return o.ctx.SetXattr(o.name, name, value)
+}
+
+// GetXattr returns the value of an extended attribute on the object.
+func (o *Object) GetXattr(name string) ([]byte, error) {
+    return o.ctx.GetXattr(o.name, name)
+}
+
+// RemoveXattr removes an extended attribute from the object.
+func (o *Object) RemoveXattr(name string) error {
+    return o.ctx.RemoveXattr(o.name, name)
+}
+
+// ListXattrs returns every extended attribute set on the object.
+func (o *Object) ListXattrs() (map[string][]byte, error) {
+    return o.ctx.ListXattrs(o.name)
+}
@@ -0,0 +1,91 @@
+package rados
+
+import (
+    "bytes"
+    "testing"
+    "time"
+)
+
+func Test_RadosXattr(t *testing.T) {
+    rados, err := New("")
+    fatalOnError(t, err, "New")
+    defer rados.Release()
+
+    ctx, err := rados.NewContext("test")
+    fatalOnError(t, err, "NewContext")
+    defer ctx.Release()
+
+    oid := "test-xattr-object"
+    fatalOnError(t, ctx.Put(oid, []byte("xattr carrier")), "Put")
+    defer ctx.Remove(oid)
+
+    if _, err := ctx.GetXattr(oid, "keep_mtime"); err != ErrNotFound {
+        t.Errorf("GetXattr of a missing attribute should return ErrNotFound, got %v", err)
+    }
+
+    fatalOnError(t, ctx.SetXattr(oid, "keep_mtime", []byte("1234567890")), "SetXattr")
+
+    val, err := ctx.GetXattr(oid, "keep_mtime")
+    fatalOnError(t, err, "GetXattr")
+    if !bytes.Equal(val, []byte("1234567890")) {
+        t.Errorf("GetXattr returned %q, expected %q", val, "1234567890")
+    }
+
+    all, err := ctx.ListXattrs(oid)
+    fatalOnError(t, err, "ListXattrs")
+    if !bytes.Equal(all["keep_mtime"], []byte("1234567890")) {
+        t.Errorf("ListXattrs returned %v, expected to include keep_mtime", all)
+    }
+
+    fatalOnError(t, ctx.RemoveXattr(oid, "keep_mtime"), "RemoveXattr")
+
+    if _, err := ctx.GetXattr(oid, "keep_mtime"); err != ErrNotFound {
+        t.Errorf("GetXattr after RemoveXattr should return ErrNotFound, got %v", err)
+    }
+}
+
+func Test_RadosObjectXattr(t *testing.T) {
+    rados, err := New("")
+    fatalOnError(t, err, "New")
+    defer rados.Release()
+
+    ctx, err := rados.NewContext("test")
+    fatalOnError(t, err, "NewContext")
+    defer ctx.Release()
+
+    name := "test-object-xattr"
+    obj, err := ctx.Create(name)
+    fatalOnError(t, err, "Create")
+    defer ctx.Remove(name)
+
+    fatalOnError(t, obj.SetXattr("trash", []byte("yes")), "SetXattr")
+
+    val, err := obj.GetXattr("trash")
+    fatalOnError(t, err, "GetXattr")
+    if !bytes.Equal(val, []byte("yes")) {
+        t.Errorf("GetXattr returned %q, expected %q", val, "yes")
+    }
+}
+
+func Test_RadosObjectInfoModTime(t *testing.T) {
+    rados, err := New("")
+    fatalOnError(t, err, "New")
+    defer rados.Release()
+
+    ctx, err := rados.NewContext("test")
+    fatalOnError(t, err, "NewContext")
+    defer ctx.Release()
+
+    name := "test-modtime-object"
+    before := time.Now().Add(-time.Minute)
+
+    fatalOnError(t, ctx.Put(name, []byte("time me")), "Put")
+    defer ctx.Remove(name)
+
+    info, err := ctx.Stat(name)
+    fatalOnError(t, err, "Stat")
+
+    if info.ModTime().Before(before) {
+        t.Errorf("ModTime %v should be after %v", info.ModTime(), before)
+    }
+}